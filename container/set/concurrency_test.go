@@ -0,0 +1,119 @@
+package set_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dtynn/utils/container/set"
+)
+
+// runWithDeadline fails the test if f doesn't return within timeout, instead of hanging forever
+// on a deadlock.
+func runWithDeadline(t *testing.T, timeout time.Duration, f func()) {
+	t.Helper()
+
+	done := make(chan struct{})
+	go func() {
+		f()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(timeout):
+		t.Fatal("deadlocked")
+	}
+}
+
+func TestThreadSafeUnionReciprocalNoDeadlock(t *testing.T) {
+	a := set.NewThreadSafeStringSet("a1", "a2", "a3")
+	b := set.NewThreadSafeStringSet("b1", "b2", "b3")
+
+	runWithDeadline(t, 5*time.Second, func() {
+		var wg sync.WaitGroup
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 2000; i++ {
+				a.Union(b)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 2000; i++ {
+				b.Union(a)
+			}
+		}()
+		wg.Wait()
+	})
+}
+
+func TestThreadSafeSubtractReciprocalNoDeadlock(t *testing.T) {
+	a := set.NewThreadSafeStringSet("a1", "a2", "a3")
+	b := set.NewThreadSafeStringSet("b1", "b2", "b3")
+
+	runWithDeadline(t, 5*time.Second, func() {
+		var wg sync.WaitGroup
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 2000; i++ {
+				a.Subtract(b)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 2000; i++ {
+				b.Subtract(a)
+			}
+		}()
+		wg.Wait()
+	})
+}
+
+// TestThreadSafeIntersectReciprocalNoDeadlock reproduces the scenario from the chunk0-2 review:
+// a.Intersect(b) and b.Intersect(a) running concurrently, with a third goroutine writing to both
+// sets so that Go's RWMutex starves new readers behind the pending writer.
+func TestThreadSafeIntersectReciprocalNoDeadlock(t *testing.T) {
+	a := set.NewThreadSafeStringSet("a1", "a2", "a3")
+	b := set.NewThreadSafeStringSet("b1", "b2", "b3")
+
+	stopWriter := make(chan struct{})
+	var writerWg sync.WaitGroup
+	writerWg.Add(1)
+	go func() {
+		defer writerWg.Done()
+		for i := 0; ; i++ {
+			select {
+			case <-stopWriter:
+				return
+			default:
+			}
+			a.Add(fmt.Sprintf("a-writer-%d", i))
+			b.Add(fmt.Sprintf("b-writer-%d", i))
+		}
+	}()
+
+	runWithDeadline(t, 5*time.Second, func() {
+		var wg sync.WaitGroup
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 2000; i++ {
+				a.Intersect(b)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 2000; i++ {
+				b.Intersect(a)
+			}
+		}()
+		wg.Wait()
+	})
+
+	close(stopWriter)
+	writerWg.Wait()
+}
@@ -0,0 +1,509 @@
+// Package generic provides a Set[T comparable] that mirrors the operation surface of
+// container/set, without being hard-wired to string elements.
+package generic
+
+import "sync"
+
+// An unordered collection of unique comparable elements which supports lookups, insertions,
+// deletions, iteration, and common binary set operations.  It is not guaranteed to be
+// thread-safe; use NewThreadSafe for a variant that is.
+type Set[T comparable] interface {
+	// Returns a new Set that contains exactly the same elements as this set.
+	Copy() Set[T]
+
+	// Returns the cardinality of this set.
+	Len() int
+
+	// Returns true if and only if this set contains v (according to Go equality rules).
+	Contains(v T) bool
+	// Inserts v into this set.
+	Add(v T)
+	// Removes v from this set, if it is present.  Returns true if and only if v was present.
+	Remove(v T) bool
+	// Removes and returns an arbitrary element from the set.  Returns false if the set is empty.
+	Pop() (T, bool)
+	// Returns all elements currently in the set, in no particular order.
+	ToSlice() []T
+
+	// Executes f(v) for every element v in this set.  If f mutates this set, behavior is undefined.
+	Do(f func(T))
+	// Executes f(v) once for every element v in the set, aborting if f ever returns false. If f
+	// mutates this set, behavior is undefined.
+	DoWhile(f func(T) bool)
+	// Returns a channel from which each element in the set can be read exactly once.  If this set
+	// is mutated before the channel is emptied, the exact data read from the channel is undefined.
+	//
+	// Deprecated: Iter spawns a goroutine that leaks if the returned channel is never drained to
+	// completion (e.g. because the caller stops ranging early). Prefer Range, which supports
+	// early termination safely.
+	Iter() <-chan T
+	// Calls yield(v) for every element v in this set, stopping as soon as yield returns false.
+	// Range has the shape of a Go 1.23 range-over-func iterator: on a module whose go directive
+	// declares Go 1.23 or later, callers can write `for v := range s.Range` and `break` out of
+	// the loop without leaking anything, unlike Iter. On earlier language versions, call it
+	// directly: s.Range(func(v T) bool { ...; return true }). If yield mutates this set, behavior
+	// is undefined.
+	Range(yield func(T) bool)
+
+	// Adds every element in s into this set.
+	Union(s Set[T])
+	// Removes every element not in s from this set.
+	Intersect(s Set[T])
+	// Removes every element in s from this set.
+	Subtract(s Set[T])
+	// Removes all elements from the set.
+	Clear()
+	// Returns true if and only if all elements in this set are elements in s.
+	IsSubset(s Set[T]) bool
+	// Returns true if and only if all elements in s are elements in this set.
+	IsSuperset(s Set[T]) bool
+	// Returns true if and only if this set and s contain exactly the same elements.
+	IsEqual(s Set[T]) bool
+	// Removes all elements v from this set that satisfy f(v) == true.
+	RemoveIf(f func(T) bool)
+}
+
+// Returns a new set which is the union of the given sets.  The inputs are unmodified.  Returns
+// an empty set if sets is empty.
+func Union[T comparable](sets ...Set[T]) Set[T] {
+	res := New[T]()
+	for _, s := range sets {
+		res.Union(s)
+	}
+	return res
+}
+
+// Returns a new set which is the intersection of the given sets.  The inputs are unmodified.
+// Returns an empty set if sets is empty.
+func Intersect[T comparable](sets ...Set[T]) Set[T] {
+	if len(sets) == 0 {
+		return New[T]()
+	}
+
+	res := sets[0].Copy()
+	for _, s := range sets[1:] {
+		res.Intersect(s)
+	}
+	return res
+}
+
+// Returns a new set containing the elements of sets[0] that are not present in any of the
+// remaining sets.  The inputs are unmodified.  Returns an empty set if sets is empty.
+func Difference[T comparable](sets ...Set[T]) Set[T] {
+	if len(sets) == 0 {
+		return New[T]()
+	}
+
+	res := sets[0].Copy()
+	for _, s := range sets[1:] {
+		res.Subtract(s)
+	}
+	return res
+}
+
+// Returns a new set containing the elements that are present in exactly one of the given sets.
+// The inputs are unmodified.
+func SymmetricDifference[T comparable](sets ...Set[T]) Set[T] {
+	res := New[T]()
+	for _, s := range sets {
+		res.Union(s)
+	}
+
+	seenByMoreThanOne := New[T]()
+	for i, s := range sets {
+		for j, other := range sets {
+			if i == j {
+				continue
+			}
+			s.DoWhile(func(v T) bool {
+				if other.Contains(v) {
+					seenByMoreThanOne.Add(v)
+				}
+				return true
+			})
+		}
+	}
+
+	res.Subtract(seenByMoreThanOne)
+	return res
+}
+
+// Returns a new, empty Set[T].
+func New[T comparable]() Set[T] {
+	return &setImpl[T]{data: make(map[T]struct{})}
+}
+
+// Returns a new, empty, thread-safe Set[T].  The returned Set guards its internal state with a
+// sync.RWMutex, so it is safe to share across goroutines.
+func NewThreadSafe[T comparable]() Set[T] {
+	return &tsSetImpl[T]{data: make(map[T]struct{})}
+}
+
+// Returns a new Set[T] pre-populated with the elements of items.
+func FromSlice[T comparable](items []T) Set[T] {
+	res := New[T]()
+	for _, item := range items {
+		res.Add(item)
+	}
+	return res
+}
+
+type setImpl[T comparable] struct {
+	data map[T]struct{}
+}
+
+func (s *setImpl[T]) Len() int {
+	return len(s.data)
+}
+
+func (s *setImpl[T]) Copy() Set[T] {
+	res := &setImpl[T]{data: make(map[T]struct{}, len(s.data))}
+	for key := range s.data {
+		res.data[key] = struct{}{}
+	}
+	return res
+}
+
+func (s *setImpl[T]) Clear() {
+	s.data = make(map[T]struct{})
+}
+
+func (s *setImpl[T]) Contains(v T) bool {
+	_, ok := s.data[v]
+	return ok
+}
+
+func (s *setImpl[T]) Add(v T) {
+	s.data[v] = struct{}{}
+}
+
+func (s *setImpl[T]) Remove(v T) bool {
+	_, ok := s.data[v]
+	if ok {
+		delete(s.data, v)
+	}
+	return ok
+}
+
+func (s *setImpl[T]) Pop() (T, bool) {
+	for key := range s.data {
+		delete(s.data, key)
+		return key, true
+	}
+	var zero T
+	return zero, false
+}
+
+func (s *setImpl[T]) Do(f func(T)) {
+	for key := range s.data {
+		f(key)
+	}
+}
+
+func (s *setImpl[T]) DoWhile(f func(T) bool) {
+	for key := range s.data {
+		if !f(key) {
+			break
+		}
+	}
+}
+
+func (s *setImpl[T]) Iter() <-chan T {
+	iter := make(chan T)
+	go func() {
+		for key := range s.data {
+			iter <- key
+		}
+		close(iter)
+	}()
+	return iter
+}
+
+func (s *setImpl[T]) Range(yield func(T) bool) {
+	for key := range s.data {
+		if !yield(key) {
+			return
+		}
+	}
+}
+
+func (s *setImpl[T]) Union(s2 Set[T]) {
+	s2.Do(func(item T) { s.Add(item) })
+}
+
+func (s *setImpl[T]) Intersect(s2 Set[T]) {
+	var toRemove []T
+	for key := range s.data {
+		if !s2.Contains(key) {
+			toRemove = append(toRemove, key)
+		}
+	}
+
+	for _, key := range toRemove {
+		s.Remove(key)
+	}
+}
+
+func (s *setImpl[T]) Subtract(s2 Set[T]) {
+	s2.Do(func(item T) { s.Remove(item) })
+}
+
+func (s *setImpl[T]) IsSubset(s2 Set[T]) (isSubset bool) {
+	isSubset = true
+	s.DoWhile(func(item T) bool {
+		if !s2.Contains(item) {
+			isSubset = false
+		}
+		return isSubset
+	})
+	return
+}
+
+func (s *setImpl[T]) IsSuperset(s2 Set[T]) bool {
+	return s2.IsSubset(s)
+}
+
+func (s *setImpl[T]) IsEqual(s2 Set[T]) bool {
+	if s.Len() != s2.Len() {
+		return false
+	}
+	return s.IsSubset(s2)
+}
+
+func (s *setImpl[T]) RemoveIf(f func(T) bool) {
+	var toRemove []T
+	for item := range s.data {
+		if f(item) {
+			toRemove = append(toRemove, item)
+		}
+	}
+
+	for _, item := range toRemove {
+		s.Remove(item)
+	}
+}
+
+func (s *setImpl[T]) ToSlice() []T {
+	result := make([]T, 0, len(s.data))
+	for key := range s.data {
+		result = append(result, key)
+	}
+	return result
+}
+
+// A thread-safe implementation of Set[T].  Read operations take an RLock and mutations take a
+// write lock.  Binary operations snapshot the other operand before locking so that two
+// tsSetImpl values can never deadlock against each other.
+type tsSetImpl[T comparable] struct {
+	mu   sync.RWMutex
+	data map[T]struct{}
+}
+
+func (s *tsSetImpl[T]) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.data)
+}
+
+func (s *tsSetImpl[T]) Copy() Set[T] {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	res := &tsSetImpl[T]{data: make(map[T]struct{}, len(s.data))}
+	for key := range s.data {
+		res.data[key] = struct{}{}
+	}
+	return res
+}
+
+func (s *tsSetImpl[T]) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data = make(map[T]struct{})
+}
+
+func (s *tsSetImpl[T]) Contains(v T) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	_, ok := s.data[v]
+	return ok
+}
+
+func (s *tsSetImpl[T]) Add(v T) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data[v] = struct{}{}
+}
+
+func (s *tsSetImpl[T]) Remove(v T) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.data[v]
+	if ok {
+		delete(s.data, v)
+	}
+	return ok
+}
+
+func (s *tsSetImpl[T]) Pop() (T, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key := range s.data {
+		delete(s.data, key)
+		return key, true
+	}
+	var zero T
+	return zero, false
+}
+
+func (s *tsSetImpl[T]) Do(f func(T)) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for key := range s.data {
+		f(key)
+	}
+}
+
+func (s *tsSetImpl[T]) DoWhile(f func(T) bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for key := range s.data {
+		if !f(key) {
+			break
+		}
+	}
+}
+
+func (s *tsSetImpl[T]) Iter() <-chan T {
+	s.mu.RLock()
+	keys := make([]T, 0, len(s.data))
+	for key := range s.data {
+		keys = append(keys, key)
+	}
+	s.mu.RUnlock()
+
+	iter := make(chan T)
+	go func() {
+		for _, key := range keys {
+			iter <- key
+		}
+		close(iter)
+	}()
+	return iter
+}
+
+func (s *tsSetImpl[T]) Range(yield func(T) bool) {
+	// Snapshot the keys under a brief RLock rather than holding the lock across yield, since
+	// yield may block (e.g. a channel send in IterCtx) or try to re-lock this same set.
+	s.mu.RLock()
+	keys := make([]T, 0, len(s.data))
+	for key := range s.data {
+		keys = append(keys, key)
+	}
+	s.mu.RUnlock()
+
+	for _, key := range keys {
+		if !yield(key) {
+			return
+		}
+	}
+}
+
+func tsSnapshot[T comparable](s2 Set[T]) []T {
+	items := make([]T, 0, s2.Len())
+	s2.Do(func(item T) {
+		items = append(items, item)
+	})
+	return items
+}
+
+func (s *tsSetImpl[T]) Union(s2 Set[T]) {
+	items := tsSnapshot(s2)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, item := range items {
+		s.data[item] = struct{}{}
+	}
+}
+
+func (s *tsSetImpl[T]) Intersect(s2 Set[T]) {
+	items := tsSnapshot(s2)
+	keep := make(map[T]struct{}, len(items))
+	for _, item := range items {
+		keep[item] = struct{}{}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key := range s.data {
+		if _, ok := keep[key]; !ok {
+			delete(s.data, key)
+		}
+	}
+}
+
+func (s *tsSetImpl[T]) Subtract(s2 Set[T]) {
+	items := tsSnapshot(s2)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, item := range items {
+		delete(s.data, item)
+	}
+}
+
+func (s *tsSetImpl[T]) IsSubset(s2 Set[T]) bool {
+	s.mu.RLock()
+	keys := make([]T, 0, len(s.data))
+	for key := range s.data {
+		keys = append(keys, key)
+	}
+	s.mu.RUnlock()
+
+	for _, key := range keys {
+		if !s2.Contains(key) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *tsSetImpl[T]) IsSuperset(s2 Set[T]) bool {
+	return s2.IsSubset(s)
+}
+
+func (s *tsSetImpl[T]) IsEqual(s2 Set[T]) bool {
+	if s.Len() != s2.Len() {
+		return false
+	}
+	return s.IsSubset(s2)
+}
+
+func (s *tsSetImpl[T]) RemoveIf(f func(T) bool) {
+	s.mu.RLock()
+	var toRemove []T
+	for item := range s.data {
+		if f(item) {
+			toRemove = append(toRemove, item)
+		}
+	}
+	s.mu.RUnlock()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, item := range toRemove {
+		delete(s.data, item)
+	}
+}
+
+func (s *tsSetImpl[T]) ToSlice() []T {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make([]T, 0, len(s.data))
+	for key := range s.data {
+		result = append(result, key)
+	}
+	return result
+}
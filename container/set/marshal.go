@@ -0,0 +1,109 @@
+package set
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/dtynn/utils/container/set/generic"
+)
+
+// Returns the sorted JSON array form of this set, e.g. ["a","b","c"].  Sorting makes the
+// output deterministic across calls, which map iteration order does not give us for free; use
+// MarshalJSONUnsorted if determinism isn't needed and the sort isn't worth paying for.
+func (s *setImpl) MarshalJSON() ([]byte, error) {
+	items := s.ToArray()
+	sort.Strings(items)
+	return json.Marshal(items)
+}
+
+// Like MarshalJSON, but skips the sort.  Use this when the set is large and callers don't care
+// about byte-for-byte stable output.
+func (s *setImpl) MarshalJSONUnsorted() ([]byte, error) {
+	return json.Marshal(s.ToArray())
+}
+
+// Populates this set from JSON produced by MarshalJSON/MarshalJSONUnsorted, i.e. a JSON array
+// of strings (["a","b","c"]), or from a JSON object whose boolean values mark membership
+// ({"a":true,"b":true}), so sets round-trip cleanly through either encoding.  Any existing
+// elements are discarded first.
+func (s *setImpl) UnmarshalJSON(data []byte) error {
+	var items []string
+	if err := json.Unmarshal(data, &items); err == nil {
+		return s.replaceWith(items)
+	}
+
+	var obj map[string]bool
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return fmt.Errorf("set: cannot unmarshal %s as a string array or a string->bool object", data)
+	}
+
+	members := make([]string, 0, len(obj))
+	for k, v := range obj {
+		if v {
+			members = append(members, k)
+		}
+	}
+	return s.replaceWith(members)
+}
+
+// Returns the same sorted form as MarshalJSON, but as a comma-separated string (e.g. "a,b,c")
+// rather than a JSON array, for use in contexts that want encoding.TextMarshaler (e.g. map
+// keys, env vars, query parameters).
+//
+// The comma is not escaped, so this round-trips correctly only if no element contains a comma
+// (an element of "a,b" is indistinguishable from the two elements "a" and "b"). Sets with
+// comma-bearing elements should use MarshalJSON/MarshalJSONUnsorted or GobEncode instead, neither
+// of which has this ambiguity.
+func (s *setImpl) MarshalText() ([]byte, error) {
+	items := s.ToArray()
+	sort.Strings(items)
+	return []byte(strings.Join(items, ",")), nil
+}
+
+// The inverse of MarshalText, with the same comma-escaping caveat. An empty input produces an
+// empty set rather than a set containing the empty string.
+func (s *setImpl) UnmarshalText(data []byte) error {
+	text := string(data)
+	if text == "" {
+		return s.replaceWith(nil)
+	}
+	return s.replaceWith(strings.Split(text, ","))
+}
+
+// Gob-encodes the sorted contents of this set.
+func (s *setImpl) GobEncode() ([]byte, error) {
+	items := s.ToArray()
+	sort.Strings(items)
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(items); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// The inverse of GobEncode.
+func (s *setImpl) GobDecode(data []byte) error {
+	var items []string
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&items); err != nil {
+		return err
+	}
+	return s.replaceWith(items)
+}
+
+// replaceWith discards whatever this set currently holds and repopulates it with items.
+func (s *setImpl) replaceWith(items []string) error {
+	if s.inner == nil {
+		s.inner = generic.New[string]()
+	} else {
+		s.inner.Clear()
+	}
+	for _, item := range items {
+		s.inner.Add(item)
+	}
+	return nil
+}
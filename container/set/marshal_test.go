@@ -0,0 +1,148 @@
+package set_test
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/gob"
+	"encoding/json"
+	"testing"
+
+	"github.com/dtynn/utils/container/set"
+)
+
+func TestMarshalJSONIsSorted(t *testing.T) {
+	s := set.NewStringSet("c", "a", "b")
+
+	data, err := json.Marshal(s)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if got, want := string(data), `["a","b","c"]`; got != want {
+		t.Errorf("MarshalJSON = %s, want %s", got, want)
+	}
+}
+
+func TestJSONRoundTripArrayForm(t *testing.T) {
+	s := set.NewStringSet("c", "a", "b")
+
+	data, err := json.Marshal(s)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+
+	got := set.NewStringSet()
+	if err := json.Unmarshal(data, got); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if !got.IsEqual(s) {
+		t.Errorf("round-tripped set = %v, want %v", got.ToArray(), s.ToArray())
+	}
+}
+
+func TestJSONUnmarshalObjectForm(t *testing.T) {
+	got := set.NewStringSet()
+	if err := json.Unmarshal([]byte(`{"a":true,"b":false,"c":true}`), got); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+
+	want := set.NewStringSet("a", "c")
+	if !got.IsEqual(want) {
+		t.Errorf("Unmarshal object form = %v, want %v", got.ToArray(), want.ToArray())
+	}
+}
+
+func TestMarshalJSONUnsortedRoundTrip(t *testing.T) {
+	s := set.NewStringSet("x", "y", "z")
+
+	mj, ok := s.(interface{ MarshalJSONUnsorted() ([]byte, error) })
+	if !ok {
+		t.Fatal("Set returned by NewStringSet does not implement MarshalJSONUnsorted")
+	}
+	data, err := mj.MarshalJSONUnsorted()
+	if err != nil {
+		t.Fatalf("MarshalJSONUnsorted: %v", err)
+	}
+
+	got := set.NewStringSet()
+	if err := json.Unmarshal(data, got); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if !got.IsEqual(s) {
+		t.Errorf("round-tripped set = %v, want %v", got.ToArray(), s.ToArray())
+	}
+}
+
+func TestTextRoundTrip(t *testing.T) {
+	s := set.NewStringSet("a", "b", "c")
+
+	data, err := s.(encoding.TextMarshaler).MarshalText()
+	if err != nil {
+		t.Fatalf("MarshalText: %v", err)
+	}
+
+	got := set.NewStringSet()
+	if err := got.(encoding.TextUnmarshaler).UnmarshalText(data); err != nil {
+		t.Fatalf("UnmarshalText: %v", err)
+	}
+	if !got.IsEqual(s) {
+		t.Errorf("round-tripped set = %v, want %v", got.ToArray(), s.ToArray())
+	}
+}
+
+func TestTextRoundTripEmpty(t *testing.T) {
+	s := set.NewStringSet()
+
+	data, err := s.(encoding.TextMarshaler).MarshalText()
+	if err != nil {
+		t.Fatalf("MarshalText: %v", err)
+	}
+	if len(data) != 0 {
+		t.Errorf("MarshalText of an empty set = %q, want empty", data)
+	}
+
+	got := set.NewStringSet("stale")
+	if err := got.(encoding.TextUnmarshaler).UnmarshalText(data); err != nil {
+		t.Fatalf("UnmarshalText: %v", err)
+	}
+	if got.Len() != 0 {
+		t.Errorf("UnmarshalText(%q) produced a set of length %d, want 0: %v", data, got.Len(), got.ToArray())
+	}
+}
+
+// TestTextCommaCaveat documents the MarshalText/UnmarshalText limitation called out in the
+// chunk0-5 review: an element containing a comma is indistinguishable from two elements split
+// on that comma. This pins down the documented behavior so a future change notices if it drifts.
+func TestTextCommaCaveat(t *testing.T) {
+	s := set.NewStringSet("a,b")
+
+	data, err := s.(encoding.TextMarshaler).MarshalText()
+	if err != nil {
+		t.Fatalf("MarshalText: %v", err)
+	}
+
+	got := set.NewStringSet()
+	if err := got.(encoding.TextUnmarshaler).UnmarshalText(data); err != nil {
+		t.Fatalf("UnmarshalText: %v", err)
+	}
+	want := set.NewStringSet("a", "b")
+	if !got.IsEqual(want) {
+		t.Errorf("UnmarshalText(%q) = %v, want the documented split into %v", data, got.ToArray(), want.ToArray())
+	}
+}
+
+func TestGobRoundTrip(t *testing.T) {
+	s := set.NewStringSet("a", "b", "c")
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(s); err != nil {
+		t.Fatalf("gob Encode: %v", err)
+	}
+
+	got := set.NewStringSet()
+	if err := gob.NewDecoder(&buf).Decode(got); err != nil {
+		t.Fatalf("gob Decode: %v", err)
+	}
+	if !got.IsEqual(s) {
+		t.Errorf("round-tripped set = %v, want %v", got.ToArray(), s.ToArray())
+	}
+}
@@ -0,0 +1,78 @@
+package set_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/dtynn/utils/container/set"
+)
+
+func TestPowerSetCardinality(t *testing.T) {
+	for n := 0; n <= 6; n++ {
+		items := make([]string, n)
+		for i := range items {
+			items[i] = fmt.Sprintf("item-%d", i)
+		}
+		s := set.NewStringSet(items...)
+
+		power, err := set.PowerSet(s)
+		if err != nil {
+			t.Fatalf("PowerSet(%d elements): %v", n, err)
+		}
+		if want := 1 << uint(n); len(power) != want {
+			t.Errorf("PowerSet(%d elements) returned %d subsets, want %d", n, len(power), want)
+		}
+	}
+}
+
+func TestPowerSetRejectsOversizedSets(t *testing.T) {
+	items := make([]string, 21)
+	for i := range items {
+		items[i] = fmt.Sprintf("item-%d", i)
+	}
+	s := set.NewStringSet(items...)
+
+	if _, err := set.PowerSet(s); err == nil {
+		t.Fatal("PowerSet(21 elements) should have returned an error instead of computing 2^21 subsets")
+	}
+}
+
+func TestCartesianProductCardinality(t *testing.T) {
+	a := set.NewStringSet("a1", "a2", "a3")
+	b := set.NewStringSet("b1", "b2")
+
+	pairs := set.CartesianProduct(a, b)
+	if want := a.Len() * b.Len(); len(pairs) != want {
+		t.Fatalf("CartesianProduct length = %d, want %d", len(pairs), want)
+	}
+
+	seen := make(map[[2]string]bool, len(pairs))
+	for _, p := range pairs {
+		if !a.Contains(p[0]) {
+			t.Errorf("pair %v: %q is not in the first set", p, p[0])
+		}
+		if !b.Contains(p[1]) {
+			t.Errorf("pair %v: %q is not in the second set", p, p[1])
+		}
+		seen[p] = true
+	}
+	if len(seen) != len(pairs) {
+		t.Errorf("CartesianProduct returned %d pairs but only %d were distinct", len(pairs), len(seen))
+	}
+}
+
+func TestSymmetricDifference(t *testing.T) {
+	a := set.NewStringSet("a", "b", "c")
+	b := set.NewStringSet("b", "c", "d")
+
+	got := set.SymmetricDifference(a, b)
+	want := set.NewStringSet("a", "d")
+	if !got.IsEqual(want) {
+		t.Errorf("SymmetricDifference(a, b) = %v, want %v", got.ToArray(), want.ToArray())
+	}
+
+	// a and b must be unmodified.
+	if a.Len() != 3 || b.Len() != 3 {
+		t.Errorf("SymmetricDifference mutated an input: a=%v b=%v", a.ToArray(), b.ToArray())
+	}
+}
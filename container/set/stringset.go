@@ -1,5 +1,12 @@
 package set
 
+import (
+	"context"
+	"fmt"
+
+	"github.com/dtynn/utils/container/set/generic"
+)
+
 // An unordered collection of unique elements which supports lookups, insertions, deletions,
 // iteration, and common binary set operations.  It is not guaranteed to be thread-safe.
 type Set interface {
@@ -25,7 +32,21 @@ type Set interface {
 	DoWhile(f func(string) bool)
 	// Returns a channel from which each element in the set can be read exactly once.  If this set
 	// is mutated before the channel is emptied, the exact data read from the channel is undefined.
+	//
+	// Deprecated: Iter spawns a goroutine that leaks if the returned channel is never drained to
+	// completion (e.g. because the caller stops ranging early). Prefer Range, or IterCtx if a
+	// channel is required.
 	Iter() <-chan string
+	// Calls yield(v) for every element v in this set, stopping as soon as yield returns false.
+	// Range has the shape of a Go 1.23 range-over-func iterator: on a module whose go directive
+	// declares Go 1.23 or later, callers can write `for v := range s.Range` and `break` out of
+	// the loop without leaking anything, unlike Iter. On earlier language versions, call it
+	// directly: s.Range(func(v string) bool { ...; return true }). If yield mutates this set,
+	// behavior is undefined.
+	Range(yield func(string) bool)
+	// Like Iter, but closes the returned channel as soon as ctx is done, instead of leaking a
+	// goroutine blocked on an unread channel forever.
+	IterCtx(ctx context.Context) <-chan string
 
 	// Adds every element in s into this set.
 	Union(s Set)
@@ -68,99 +89,180 @@ func Subtract(s1 Set, s2 Set) Set {
 	return s3
 }
 
-// Returns a new Set pre-populated with the given items
+// Returns a new set containing the elements present in exactly one of s1 and s2, computed as
+// Union(s1, s2) - Intersect(s1, s2).  s1 and s2 are unmodified.
+func SymmetricDifference(s1 Set, s2 Set) Set {
+	s3 := Union(s1, s2)
+	s3.Subtract(Intersect(s1, s2))
+	return s3
+}
+
+// Returns the Cartesian product of s1 and s2 as a slice of [2]string pairs, with the first
+// element of each pair drawn from s1 and the second from s2.  The order of the pairs is
+// unspecified, since it follows map iteration order over s1 and s2.
+func CartesianProduct(s1 Set, s2 Set) [][2]string {
+	result := make([][2]string, 0, s1.Len()*s2.Len())
+	s1.Do(func(a string) {
+		s2.Do(func(b string) {
+			result = append(result, [2]string{a, b})
+		})
+	})
+	return result
+}
+
+// powerSetLimit bounds the input to PowerSet, since its output grows as 2^n.
+const powerSetLimit = 20
+
+// Returns every subset of s, including the empty set and s itself.  Fails rather than risk
+// exhausting memory if s.Len() exceeds powerSetLimit, since the result has 2^n elements.
+func PowerSet(s Set) ([]Set, error) {
+	if s.Len() > powerSetLimit {
+		return nil, fmt.Errorf("set: refusing to compute the power set of a %d-element set (limit is %d)", s.Len(), powerSetLimit)
+	}
+
+	items := s.ToArray()
+	result := make([]Set, 0, 1<<uint(len(items)))
+	for mask := 0; mask < (1 << uint(len(items))); mask++ {
+		subset := NewStringSet()
+		for i, item := range items {
+			if mask&(1<<uint(i)) != 0 {
+				subset.Add(item)
+			}
+		}
+		result = append(result, subset)
+	}
+	return result, nil
+}
+
+// A Set that additionally supports removing and returning an arbitrary element.  The sets
+// returned by NewStringSet and NewThreadSafeStringSet both satisfy this interface.
+type PopSet interface {
+	Set
+
+	// Removes and returns an arbitrary element from the set.  Returns false if the set is empty.
+	Pop() (string, bool)
+}
+
+// Returns a new Set pre-populated with the given items.  The underlying storage is a
+// generic.Set[string]; this package stays string-only so existing callers don't have to deal
+// with type parameters.
 func NewStringSet(items ...string) Set {
-	res := setImpl{
-		data: make(map[string]struct{}),
+	res := &setImpl{inner: generic.New[string]()}
+	for _, item := range items {
+		res.Add(item)
 	}
+	return res
+}
+
+// Returns a new thread-safe Set pre-populated with the given items.  Unlike the Set returned by
+// NewStringSet, the Set returned here guards its internal state with a sync.RWMutex, so it is
+// safe to share across goroutines.
+func NewThreadSafeStringSet(items ...string) Set {
+	res := &setImpl{inner: generic.NewThreadSafe[string]()}
 	for _, item := range items {
 		res.Add(item)
 	}
 	return res
 }
 
+// setImpl is a thin wrapper around generic.Set[string] that adapts its method names (ToSlice,
+// Clear) to the names this package has always exposed (ToArray, Init).  Whether the underlying
+// set is thread-safe is decided entirely by the constructor used to build inner.
 type setImpl struct {
-	data map[string]struct{}
+	inner generic.Set[string]
 }
 
-func (s setImpl) Len() int {
-	return len(s.data)
+func (s *setImpl) Len() int {
+	return s.inner.Len()
 }
 
-func (s setImpl) Copy() Set {
-	res := NewStringSet()
-	res.Union(s)
-	return res
+func (s *setImpl) Copy() Set {
+	return &setImpl{inner: s.inner.Copy()}
 }
 
-func (s setImpl) Init() {
-	s.data = make(map[string]struct{})
+func (s *setImpl) Init() {
+	s.inner.Clear()
 }
 
-func (s setImpl) Contains(v string) bool {
-	_, ok := s.data[v]
-	return ok
+func (s *setImpl) Contains(v string) bool {
+	return s.inner.Contains(v)
 }
 
-func (s setImpl) Add(v string) {
-	s.data[v] = struct{}{}
+func (s *setImpl) Add(v string) {
+	s.inner.Add(v)
 }
 
-func (s setImpl) Remove(v string) bool {
-	_, ok := s.data[v]
-	if ok {
-		delete(s.data, v)
-	}
-	return ok
+func (s *setImpl) Remove(v string) bool {
+	return s.inner.Remove(v)
 }
 
-func (s setImpl) Do(f func(string)) {
-	for key := range s.data {
-		f(key)
-	}
+func (s *setImpl) Pop() (string, bool) {
+	return s.inner.Pop()
 }
 
-func (s setImpl) DoWhile(f func(string) bool) {
-	for key := range s.data {
-		if !f(key) {
-			break
-		}
-	}
+func (s *setImpl) Do(f func(string)) {
+	s.inner.Do(f)
+}
+
+func (s *setImpl) DoWhile(f func(string) bool) {
+	s.inner.DoWhile(f)
+}
+
+func (s *setImpl) Iter() <-chan string {
+	return s.inner.Iter()
 }
 
-func (s setImpl) Iter() <-chan string {
-	iter := make(chan string)
+func (s *setImpl) Range(yield func(string) bool) {
+	s.inner.Range(yield)
+}
+
+func (s *setImpl) IterCtx(ctx context.Context) <-chan string {
+	out := make(chan string)
 	go func() {
-		for key := range s.data {
-			iter <- key
-		}
-		close(iter)
+		defer close(out)
+		s.Range(func(v string) bool {
+			select {
+			case out <- v:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		})
 	}()
-	return iter
+	return out
 }
 
-func (s setImpl) Union(s2 Set) {
-	s2.Do(func(item string) { s.Add(item) })
+func (s *setImpl) Union(s2 Set) {
+	// Snapshot s2 into a slice before mutating s: s2.Do would otherwise hold s2's RLock for the
+	// whole loop while we take s's write lock inside it, which deadlocks against a concurrent
+	// s2.Union(s) doing the same thing in reverse.
+	for _, item := range s2.ToArray() {
+		s.Add(item)
+	}
 }
 
-func (s setImpl) Intersect(s2 Set) {
-	var toRemove []string = nil
-	for key := range s.data {
-		if !s2.Contains(key) {
-			toRemove = append(toRemove, key)
-		}
+func (s *setImpl) Intersect(s2 Set) {
+	// Snapshot s2 into a set of keys to keep before calling RemoveIf: RemoveIf's predicate runs
+	// while s's own Do holds s's RLock, so calling s2.Contains from inside it would take s2's
+	// RLock while s's is already held, which deadlocks against a concurrent s2.Intersect(s).
+	keep := make(map[string]struct{})
+	for _, item := range s2.ToArray() {
+		keep[item] = struct{}{}
 	}
 
-	for _, key := range toRemove {
-		s.Remove(key)
-	}
+	s.RemoveIf(func(item string) bool {
+		_, ok := keep[item]
+		return !ok
+	})
 }
 
-func (s setImpl) Subtract(s2 Set) {
-	s2.Do(func(item string) { s.Remove(item) })
+func (s *setImpl) Subtract(s2 Set) {
+	for _, item := range s2.ToArray() {
+		s.Remove(item)
+	}
 }
 
-func (s setImpl) IsSubset(s2 Set) (isSubset bool) {
+func (s *setImpl) IsSubset(s2 Set) (isSubset bool) {
 	isSubset = true
 	s.DoWhile(func(item string) bool {
 		if !s2.Contains(item) {
@@ -171,11 +273,11 @@ func (s setImpl) IsSubset(s2 Set) (isSubset bool) {
 	return
 }
 
-func (s setImpl) IsSuperset(s2 Set) bool {
+func (s *setImpl) IsSuperset(s2 Set) bool {
 	return s2.IsSubset(s)
 }
 
-func (s setImpl) IsEqual(s2 Set) bool {
+func (s *setImpl) IsEqual(s2 Set) bool {
 	if s.Len() != s2.Len() {
 		return false
 	}
@@ -183,26 +285,19 @@ func (s setImpl) IsEqual(s2 Set) bool {
 	return s.IsSubset(s2)
 }
 
-func (s setImpl) RemoveIf(f func(string) bool) {
+func (s *setImpl) RemoveIf(f func(string) bool) {
 	var toRemove []string
-	for item := range s.data {
+	s.Do(func(item string) {
 		if f(item) {
 			toRemove = append(toRemove, item)
 		}
-	}
+	})
 
 	for _, item := range toRemove {
 		s.Remove(item)
 	}
 }
 
-func (s setImpl) ToArray() []string {
-	result := make([]string, len(s.data))
-	pos := 0
-	for key, _ := range s.data {
-		result[pos] = key
-		pos++
-	}
-
-	return result
+func (s *setImpl) ToArray() []string {
+	return s.inner.ToSlice()
 }